@@ -5,12 +5,22 @@ import (
 	"encoding/binary"
 	"errors"
 	json "github.com/json-iterator/go"
-	"sort"
-	"sync"
 	"github.com/tinychain/tinychain/common"
+	"github.com/tinychain/tinychain/core/rlp"
 	"github.com/tinychain/tinychain/db"
+	"sort"
+	"sync"
 )
 
+// SlotEntry is a Bucket's (key, value) pair as hashed: encoding the pair,
+// not just the value, means two buckets with the same values under
+// different keys don't collide. Exported because Proof carries sibling
+// entries needed to recompute a bucket's hash (see proof.go).
+type SlotEntry struct {
+	Key   string
+	Value []byte
+}
+
 type Bucket struct {
 	lock  sync.RWMutex
 	H     common.Hash       `json:"hash"`
@@ -28,19 +38,21 @@ func (bk *Bucket) Hash() common.Hash {
 	return bk.H
 }
 
-// Compute hash
+// Compute hash. Slots are RLP-encoded rather than hashed as a raw byte
+// concatenation, so the hash doesn't depend on how the bytes are joined.
 func (bk *Bucket) computeHash() common.Hash {
 	bk.lock.Lock()
 	defer bk.lock.Unlock()
-	var bytes []byte
 	// Sort the keys array in increasing order
 	if !sort.StringsAreSorted(bk.Keys) {
 		sort.Strings(bk.Keys)
 	}
-	for _, key := range bk.Keys {
-		bytes = append(bytes, bk.Slots[key]...)
+	entries := make([]SlotEntry, len(bk.Keys))
+	for i, key := range bk.Keys {
+		entries[i] = SlotEntry{Key: key, Value: bk.Slots[key]}
 	}
-	bk.H = common.Sha256(bytes)
+	data, _ := rlp.EncodeToBytes(entries)
+	bk.H = common.Sha256(data)
 	return bk.H
 }
 
@@ -0,0 +1,160 @@
+package bmt
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/tinychain/tinychain/common"
+	"github.com/tinychain/tinychain/core/rlp"
+)
+
+// DefaultBucketCap is the number of buckets a WriteSet is spread across
+// by Hash/Prove when no already-built HashTable is available. Proving
+// and verifying a key's inclusion both rebuild the table at this cap, so
+// it must match whatever cap the WriteSet's root was originally
+// committed with.
+const DefaultBucketCap = 256
+
+// ErrKeyNotFound is returned by Prove when key isn't present in ws.
+var ErrKeyNotFound = errors.New("bmt: key not found in write set")
+
+// WriteSet is a flat key/value view of a hash table's contents, keyed by
+// whatever identity the caller hashes entries under (e.g. a tx hash
+// string).
+type WriteSet map[string][]byte
+
+// Proof is a Merkle-style inclusion proof for a single key against a
+// WriteSet's bmt root: enough of the target key's bucket to recompute
+// that bucket's hash, plus every other bucket's hash, to recompute the
+// top-level root without needing the rest of the write set.
+type Proof struct {
+	// BucketIndex is the target key's bucket, per HashTable.getIndex.
+	BucketIndex int
+	// Siblings are the target bucket's other entries, excluding the key
+	// being proven, in the sorted-key order Bucket.computeHash hashes
+	// them in.
+	Siblings []SlotEntry
+	// OtherBucketHashes holds every bucket's hash except BucketIndex's,
+	// in index order (0..DefaultBucketCap-1, BucketIndex skipped) - the
+	// same order HashTable.commit iterates ht.buckets in.
+	OtherBucketHashes []common.Hash
+}
+
+// Hash builds a HashTable over ws at DefaultBucketCap and returns its
+// root: the hash of every bucket's hash, concatenated in index order.
+func Hash(ws WriteSet) (common.Hash, error) {
+	ht, err := writeSetTable(ws)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return rootHash(ht.bucketHashes()), nil
+}
+
+// Prove returns an inclusion proof for key in ws, verifiable against
+// Hash(ws)'s root via VerifyProof without needing the rest of ws.
+func Prove(key string, ws WriteSet) (*Proof, error) {
+	ht, err := writeSetTable(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	index := ht.getIndex(key)
+	bucket := ht.buckets[index]
+	if bucket == nil || !bucket.hasKey(key) {
+		return nil, ErrKeyNotFound
+	}
+
+	sortedKeys := append([]string(nil), bucket.Keys...)
+	sort.Strings(sortedKeys)
+	siblings := make([]SlotEntry, 0, len(sortedKeys)-1)
+	for _, k := range sortedKeys {
+		if k == key {
+			continue
+		}
+		siblings = append(siblings, SlotEntry{Key: k, Value: bucket.Slots[k]})
+	}
+
+	hashes := ht.bucketHashes()
+	others := make([]common.Hash, 0, len(hashes)-1)
+	for i, h := range hashes {
+		if i != index {
+			others = append(others, h)
+		}
+	}
+
+	return &Proof{
+		BucketIndex:       index,
+		Siblings:          siblings,
+		OtherBucketHashes: others,
+	}, nil
+}
+
+// VerifyProof reports whether p proves that key maps to value under
+// root, recomputing the target bucket's hash from key/value/Siblings
+// and the top-level root from p.OtherBucketHashes the same way Hash
+// does, without needing the rest of the write set.
+func VerifyProof(root common.Hash, key string, value []byte, p *Proof) bool {
+	if p == nil || p.BucketIndex < 0 || p.BucketIndex >= DefaultBucketCap {
+		return false
+	}
+	if len(p.OtherBucketHashes) != DefaultBucketCap-1 {
+		return false
+	}
+
+	entries := append([]SlotEntry(nil), p.Siblings...)
+	entries = append(entries, SlotEntry{Key: key, Value: value})
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	data, err := rlp.EncodeToBytes(entries)
+	if err != nil {
+		return false
+	}
+	bucketHash := common.Sha256(data)
+
+	hashes := make([]common.Hash, DefaultBucketCap)
+	hashes[p.BucketIndex] = bucketHash
+	j := 0
+	for i := 0; i < DefaultBucketCap; i++ {
+		if i == p.BucketIndex {
+			continue
+		}
+		hashes[i] = p.OtherBucketHashes[j]
+		j++
+	}
+
+	return rootHash(hashes) == root
+}
+
+// writeSetTable spreads ws across a fresh, in-memory HashTable (no db,
+// so nothing is persisted) at DefaultBucketCap.
+func writeSetTable(ws WriteSet) (*HashTable, error) {
+	ht := NewHashTable(nil, DefaultBucketCap)
+	for key, value := range ws {
+		if err := ht.put(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return ht, nil
+}
+
+// bucketHashes returns every bucket's hash in index order, the zero hash
+// standing in for a bucket that was never populated.
+func (ht *HashTable) bucketHashes() []common.Hash {
+	hashes := make([]common.Hash, len(ht.buckets))
+	for i, b := range ht.buckets {
+		if b != nil {
+			hashes[i] = b.computeHash()
+		}
+	}
+	return hashes
+}
+
+// rootHash hashes the concatenation of bucketHashes, in order, into a
+// single top-level root.
+func rootHash(bucketHashes []common.Hash) common.Hash {
+	var buf []byte
+	for _, h := range bucketHashes {
+		buf = append(buf, h[:]...)
+	}
+	return common.Sha256(buf)
+}
@@ -0,0 +1,100 @@
+package bmt
+
+import (
+	"testing"
+)
+
+func testWriteSet() WriteSet {
+	return WriteSet{
+		"key0001": []byte("value1"),
+		"key0002": []byte("value2"),
+		"key0003": []byte("value3"),
+	}
+}
+
+func TestProveVerifyProofRoundTrip(t *testing.T) {
+	ws := testWriteSet()
+	root, err := Hash(ws)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	for key, value := range ws {
+		proof, err := Prove(key, ws)
+		if err != nil {
+			t.Fatalf("Prove(%q): %v", key, err)
+		}
+		if !VerifyProof(root, key, value, proof) {
+			t.Fatalf("VerifyProof(%q) should succeed against the matching root", key)
+		}
+	}
+}
+
+func TestVerifyProofRejectsTamperedValue(t *testing.T) {
+	ws := testWriteSet()
+	root, err := Hash(ws)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	proof, err := Prove("key0002", ws)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	if VerifyProof(root, "key0002", []byte("tampered"), proof) {
+		t.Fatalf("VerifyProof should reject a tampered value")
+	}
+}
+
+func TestVerifyProofRejectsWrongKey(t *testing.T) {
+	ws := testWriteSet()
+	root, err := Hash(ws)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	proof, err := Prove("key0002", ws)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	if VerifyProof(root, "key0003", ws["key0002"], proof) {
+		t.Fatalf("VerifyProof should reject a proof presented for the wrong key")
+	}
+}
+
+func TestVerifyProofRejectsTruncatedOtherBucketHashes(t *testing.T) {
+	ws := testWriteSet()
+	root, err := Hash(ws)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	proof, err := Prove("key0001", ws)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	proof.OtherBucketHashes = proof.OtherBucketHashes[:len(proof.OtherBucketHashes)-1]
+	if VerifyProof(root, "key0001", ws["key0001"], proof) {
+		t.Fatalf("VerifyProof should reject a proof with a truncated OtherBucketHashes")
+	}
+}
+
+func TestVerifyProofRejectsWrongBucketIndex(t *testing.T) {
+	ws := testWriteSet()
+	root, err := Hash(ws)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	proof, err := Prove("key0001", ws)
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	proof.BucketIndex = (proof.BucketIndex + 1) % DefaultBucketCap
+	if VerifyProof(root, "key0001", ws["key0001"], proof) {
+		t.Fatalf("VerifyProof should reject a proof claiming the wrong bucket index")
+	}
+}
+
+func TestProveKeyNotFound(t *testing.T) {
+	ws := testWriteSet()
+	if _, err := Prove("key9999", ws); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
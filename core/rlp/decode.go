@@ -0,0 +1,243 @@
+package rlp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+var (
+	ErrDecodeNonPointer = errors.New("rlp: Decode requires a non-nil pointer")
+	ErrUnexpectedEOF    = errors.New("rlp: unexpected end of input")
+	ErrExpectedString   = errors.New("rlp: expected string, got list")
+	ErrExpectedList     = errors.New("rlp: expected list, got string")
+)
+
+var bigIntPtrType = reflect.TypeOf((*big.Int)(nil))
+
+// DecodeBytes parses the canonical RLP encoding in data into val, which
+// must be a non-nil pointer.
+func DecodeBytes(data []byte, val interface{}) error {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrDecodeNonPointer
+	}
+	content, isList, _, err := readHeader(data)
+	if err != nil {
+		return err
+	}
+	return decodeValue(content, isList, rv.Elem())
+}
+
+// readHeader parses the RLP header at the start of data, returning the
+// item's content, whether it's a list, and the remaining bytes after it.
+func readHeader(data []byte) (content []byte, isList bool, rest []byte, err error) {
+	if len(data) == 0 {
+		return nil, false, nil, ErrUnexpectedEOF
+	}
+	b := data[0]
+	switch {
+	case b < 0x80:
+		return data[:1], false, data[1:], nil
+	case b < 0xb8:
+		size := int(b - 0x80)
+		if len(data) < 1+size {
+			return nil, false, nil, ErrUnexpectedEOF
+		}
+		return data[1 : 1+size], false, data[1+size:], nil
+	case b < 0xc0:
+		lenOfLen := int(b - 0xb7)
+		if len(data) < 1+lenOfLen {
+			return nil, false, nil, ErrUnexpectedEOF
+		}
+		size := decodeLength(data[1 : 1+lenOfLen])
+		start := 1 + lenOfLen
+		if len(data) < start+size {
+			return nil, false, nil, ErrUnexpectedEOF
+		}
+		return data[start : start+size], false, data[start+size:], nil
+	case b < 0xf8:
+		size := int(b - 0xc0)
+		if len(data) < 1+size {
+			return nil, false, nil, ErrUnexpectedEOF
+		}
+		return data[1 : 1+size], true, data[1+size:], nil
+	default:
+		lenOfLen := int(b - 0xf7)
+		if len(data) < 1+lenOfLen {
+			return nil, false, nil, ErrUnexpectedEOF
+		}
+		size := decodeLength(data[1 : 1+lenOfLen])
+		start := 1 + lenOfLen
+		if len(data) < start+size {
+			return nil, false, nil, ErrUnexpectedEOF
+		}
+		return data[start : start+size], true, data[start+size:], nil
+	}
+}
+
+func decodeLength(b []byte) int {
+	n := 0
+	for _, c := range b {
+		n = n<<8 | int(c)
+	}
+	return n
+}
+
+// splitList splits a list's raw content into the byte ranges of its
+// top-level items, each still including its own header.
+func splitList(content []byte) ([][]byte, error) {
+	var items [][]byte
+	for len(content) > 0 {
+		_, _, rest, err := readHeader(content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, content[:len(content)-len(rest)])
+		content = rest
+	}
+	return items, nil
+}
+
+func decodeValue(content []byte, isList bool, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.Type() == bigIntPtrType {
+			if isList {
+				return ErrExpectedString
+			}
+			v.Set(reflect.ValueOf(new(big.Int).SetBytes(content)))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(content, isList, v.Elem())
+
+	case reflect.String:
+		if isList {
+			return ErrExpectedString
+		}
+		v.SetString(string(content))
+		return nil
+
+	case reflect.Bool:
+		if isList {
+			return ErrExpectedString
+		}
+		v.SetBool(len(content) > 0 && content[0] != 0)
+		return nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if isList {
+			return ErrExpectedString
+		}
+		var n uint64
+		for _, b := range content {
+			n = n<<8 | uint64(b)
+		}
+		v.SetUint(n)
+		return nil
+
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if isList {
+				return ErrExpectedString
+			}
+			cp := make([]byte, len(content))
+			copy(cp, content)
+			v.SetBytes(cp)
+			return nil
+		}
+		if !isList {
+			return ErrExpectedList
+		}
+		items, err := splitList(content)
+		if err != nil {
+			return err
+		}
+		out := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			itemContent, itemIsList, _, err := readHeader(item)
+			if err != nil {
+				return err
+			}
+			if err := decodeValue(itemContent, itemIsList, out.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(out)
+		return nil
+
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if isList {
+				return ErrExpectedString
+			}
+			for i := 0; i < v.Len(); i++ {
+				var b byte
+				if i < len(content) {
+					b = content[i]
+				}
+				v.Index(i).SetUint(uint64(b))
+			}
+			return nil
+		}
+		if !isList {
+			return ErrExpectedList
+		}
+		items, err := splitList(content)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < v.Len() && i < len(items); i++ {
+			itemContent, itemIsList, _, err := readHeader(items[i])
+			if err != nil {
+				return err
+			}
+			if err := decodeValue(itemContent, itemIsList, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Struct:
+		if v.Type() == bigIntType {
+			if isList {
+				return ErrExpectedString
+			}
+			v.Set(reflect.ValueOf(*new(big.Int).SetBytes(content)))
+			return nil
+		}
+		if !isList {
+			return ErrExpectedList
+		}
+		items, err := splitList(content)
+		if err != nil {
+			return err
+		}
+		idx := 0
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue // unexported, not part of the canonical encoding
+			}
+			if idx >= len(items) {
+				return fmt.Errorf("rlp: too few fields decoding %s", t.Name())
+			}
+			itemContent, itemIsList, _, err := readHeader(items[idx])
+			if err != nil {
+				return err
+			}
+			if err := decodeValue(itemContent, itemIsList, v.Field(i)); err != nil {
+				return err
+			}
+			idx++
+		}
+		return nil
+
+	default:
+		return ErrUnsupportedType
+	}
+}
@@ -0,0 +1,186 @@
+// Package rlp implements a small, self-contained canonical binary
+// encoding in the spirit of Ethereum's RLP: every value encodes to
+// exactly one byte sequence, independent of Go map iteration order or
+// encoder library version. tinychain uses it wherever bytes feed a hash
+// (tx signing/identity hashes, bmt bucket hashes) and keeps JSON for
+// everything human-facing.
+//
+// Encoding rules:
+//   - a byte string shorter than 56 bytes is encoded as a single prefix
+//     byte (0x80+len) followed by the string, except a single byte below
+//     0x80 which encodes as itself;
+//   - longer byte strings are prefixed with 0xb7+length-of-length
+//     followed by the length, followed by the string;
+//   - lists (structs, slices, arrays) follow the same scheme starting
+//     from 0xc0 instead of 0x80, wrapping the concatenated encoding of
+//     their items.
+package rlp
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"reflect"
+)
+
+var (
+	ErrUnsupportedType = errors.New("rlp: unsupported type")
+	ErrNegativeBigInt  = errors.New("rlp: cannot encode negative big.Int")
+)
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// EncodeToBytes returns the canonical RLP encoding of val.
+func EncodeToBytes(val interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, val); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode writes the canonical RLP encoding of val to w.
+func Encode(w *bytes.Buffer, val interface{}) error {
+	return encodeValue(w, reflect.ValueOf(val))
+}
+
+func encodeValue(w *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		return encodeBytes(w, nil)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return encodeBytes(w, nil)
+		}
+		return encodeValue(w, v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return encodeBytes(w, nil)
+		}
+		return encodeValue(w, v.Elem())
+
+	case reflect.String:
+		return encodeBytes(w, []byte(v.String()))
+
+	case reflect.Bool:
+		if v.Bool() {
+			return encodeBytes(w, []byte{1})
+		}
+		return encodeBytes(w, nil)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return encodeUint(w, v.Uint())
+
+	case reflect.Slice, reflect.Array:
+		if isByteSliceOrArray(v) {
+			return encodeBytes(w, valueBytes(v))
+		}
+		var items bytes.Buffer
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeValue(&items, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return encodeListHeader(w, items.Bytes())
+
+	case reflect.Struct:
+		if v.Type() == bigIntType {
+			bi := v.Interface().(big.Int)
+			return encodeBigInt(w, &bi)
+		}
+		var items bytes.Buffer
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).PkgPath != "" {
+				continue // unexported field, not part of the canonical encoding
+			}
+			if err := encodeValue(&items, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		return encodeListHeader(w, items.Bytes())
+
+	default:
+		return ErrUnsupportedType
+	}
+}
+
+func encodeBigInt(w *bytes.Buffer, bi *big.Int) error {
+	if bi == nil {
+		return encodeBytes(w, nil)
+	}
+	if bi.Sign() < 0 {
+		return ErrNegativeBigInt
+	}
+	return encodeBytes(w, bi.Bytes())
+}
+
+func valueBytes(v reflect.Value) []byte {
+	if v.Kind() == reflect.Slice {
+		return v.Bytes()
+	}
+	b := make([]byte, v.Len())
+	for i := range b {
+		b[i] = byte(v.Index(i).Uint())
+	}
+	return b
+}
+
+func isByteSliceOrArray(v reflect.Value) bool {
+	return v.Type().Elem().Kind() == reflect.Uint8
+}
+
+// encodeUint writes n as a minimal big-endian byte string, the canonical
+// RLP representation of an unsigned integer (0 encodes as the empty
+// string).
+func encodeUint(w *bytes.Buffer, n uint64) error {
+	if n == 0 {
+		return encodeBytes(w, nil)
+	}
+	var buf [8]byte
+	i := 8
+	for n > 0 {
+		i--
+		buf[i] = byte(n)
+		n >>= 8
+	}
+	return encodeBytes(w, buf[i:])
+}
+
+func encodeBytes(w *bytes.Buffer, b []byte) error {
+	switch {
+	case len(b) == 1 && b[0] < 0x80:
+		w.WriteByte(b[0])
+	case len(b) < 56:
+		w.WriteByte(0x80 + byte(len(b)))
+		w.Write(b)
+	default:
+		writeLengthPrefix(w, 0xb7, len(b))
+		w.Write(b)
+	}
+	return nil
+}
+
+func encodeListHeader(w *bytes.Buffer, items []byte) error {
+	if len(items) < 56 {
+		w.WriteByte(0xc0 + byte(len(items)))
+	} else {
+		writeLengthPrefix(w, 0xf7, len(items))
+	}
+	w.Write(items)
+	return nil
+}
+
+// writeLengthPrefix writes base+lenOfLen followed by the big-endian
+// encoding of length, used for byte strings/lists of 56 bytes or more.
+func writeLengthPrefix(w *bytes.Buffer, base byte, length int) {
+	var lenBytes []byte
+	for n := length; n > 0; n >>= 8 {
+		lenBytes = append([]byte{byte(n)}, lenBytes...)
+	}
+	w.WriteByte(base + byte(len(lenBytes)))
+	w.Write(lenBytes)
+}
@@ -0,0 +1,125 @@
+// Package txpool orders pending transactions the way a miner actually
+// needs to walk them: nonce-ascending within each sender (the only valid
+// execution order for a single account), with the senders themselves
+// ranked by whichever currently pays the most gas, re-ranked as each
+// sender's head transaction is consumed.
+package txpool
+
+import (
+	"container/heap"
+
+	"github.com/tinychain/tinychain/common"
+	"github.com/tinychain/tinychain/core/types"
+)
+
+// nonceHeap heapifies one sender's transactions by ascending nonce. It
+// adds the Push/Pop container/heap needs on top of the Len/Less/Swap
+// types.NonceSortedList already implements.
+type nonceHeap struct {
+	types.NonceSortedList
+}
+
+func (h *nonceHeap) Push(x interface{}) {
+	h.NonceSortedList = append(h.NonceSortedList, x.(*types.Transaction))
+}
+
+func (h *nonceHeap) Pop() interface{} {
+	old := h.NonceSortedList
+	n := len(old)
+	tx := old[n-1]
+	h.NonceSortedList = old[:n-1]
+	return tx
+}
+
+// head is one sender's current lowest-nonce transaction, the unit
+// PriceHeap orders across senders.
+type head struct {
+	from common.Address
+	tx   *types.Transaction
+}
+
+// PriceHeap orders one head transaction per sender by descending gas
+// price, so its root is always the highest-paying account's next tx.
+type PriceHeap []*head
+
+func (h PriceHeap) Len() int            { return len(h) }
+func (h PriceHeap) Less(i, j int) bool  { return h[i].tx.GasPrice() > h[j].tx.GasPrice() }
+func (h PriceHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *PriceHeap) Push(x interface{}) { *h = append(*h, x.(*head)) }
+
+func (h *PriceHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// ByPriceAndNonce walks a multi-sender set of pending transactions in
+// valid execution order: Peek always returns the highest-paying
+// account's lowest remaining nonce, and Shift/Pop advance past it once
+// the miner has either included it or rejected its whole account.
+type ByPriceAndNonce struct {
+	nonces map[common.Address]*nonceHeap
+	prices PriceHeap
+}
+
+// NewByPriceAndNonce builds a ByPriceAndNonce over txs, already grouped
+// by sender (the caller resolves Sender(signer) once per tx up front).
+func NewByPriceAndNonce(txs map[common.Address]types.Transactions) *ByPriceAndNonce {
+	b := &ByPriceAndNonce{
+		nonces: make(map[common.Address]*nonceHeap, len(txs)),
+		prices: make(PriceHeap, 0, len(txs)),
+	}
+	for from, accTxs := range txs {
+		if len(accTxs) == 0 {
+			continue
+		}
+		nh := &nonceHeap{NonceSortedList: append(types.NonceSortedList(nil), accTxs...)}
+		heap.Init(nh)
+		b.nonces[from] = nh
+		b.prices = append(b.prices, &head{from: from, tx: nh.NonceSortedList[0]})
+	}
+	heap.Init(&b.prices)
+	return b
+}
+
+// Peek returns the next transaction to execute, or nil once every
+// account's transactions have been consumed.
+func (b *ByPriceAndNonce) Peek() *types.Transaction {
+	if len(b.prices) == 0 {
+		return nil
+	}
+	return b.prices[0].tx
+}
+
+// Shift advances past the current head transaction: it's been included,
+// so its account's next-lowest-nonce tx (if any) becomes the new head
+// and the account is re-ranked in the price heap by that tx's price.
+// Every other account's order is untouched.
+func (b *ByPriceAndNonce) Shift() {
+	if len(b.prices) == 0 {
+		return
+	}
+	from := b.prices[0].from
+	nh := b.nonces[from]
+	heap.Pop(nh)
+	if nh.Len() == 0 {
+		heap.Pop(&b.prices)
+		delete(b.nonces, from)
+		return
+	}
+	b.prices[0].tx = nh.NonceSortedList[0]
+	heap.Fix(&b.prices, 0)
+}
+
+// Pop discards the current head's entire account, e.g. because it
+// failed validation, leaving every other account's order untouched.
+func (b *ByPriceAndNonce) Pop() {
+	if len(b.prices) == 0 {
+		return
+	}
+	from := b.prices[0].from
+	heap.Pop(&b.prices)
+	delete(b.nonces, from)
+}
@@ -0,0 +1,85 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tinychain/tinychain/common"
+	"github.com/tinychain/tinychain/core/types"
+)
+
+func TestByPriceAndNonceOrder(t *testing.T) {
+	var senderA, senderB, to common.Address
+	senderA[0], senderB[0], to[0] = 1, 2, 3
+
+	newTx := func(nonce, gasPrice uint64) *types.Transaction {
+		return types.NewTransaction(nonce, gasPrice, 21000, big.NewInt(0), nil, senderA, to)
+	}
+
+	// senderA's nonce-1 tx outbids its own nonce-0 tx, and senderB's
+	// nonce-0 tx outbids senderA's nonce-0 tx, so a valid walk can only
+	// reach the higher-priced txs after their sender's lower nonce first.
+	txs := map[common.Address]types.Transactions{
+		senderA: {newTx(0, 10), newTx(1, 50)},
+		senderB: {newTx(0, 20), newTx(1, 5)},
+	}
+
+	b := NewByPriceAndNonce(txs)
+
+	// Every gas price below is unique, so (price, nonce) pairs alone
+	// identify the expected step. Walk: B@0(20) outbids A@0(10); once B's
+	// head advances to @1(5), A@0(10) outbids it; once A's head advances
+	// to @1(50), it outbids everything left; B@1(5) is last.
+	wantPrices := []uint64{20, 10, 50, 5}
+	wantNonces := []uint64{0, 0, 1, 1}
+
+	for i := range wantPrices {
+		tx := b.Peek()
+		if tx == nil {
+			t.Fatalf("step %d: Peek returned nil early", i)
+		}
+		if tx.GasPrice() != wantPrices[i] || tx.Nonce() != wantNonces[i] {
+			t.Fatalf("step %d: want (price %d, nonce %d), got (price %d, nonce %d)",
+				i, wantPrices[i], wantNonces[i], tx.GasPrice(), tx.Nonce())
+		}
+		b.Shift()
+	}
+
+	if tx := b.Peek(); tx != nil {
+		t.Fatalf("expected every account exhausted, got nonce %d", tx.Nonce())
+	}
+}
+
+func TestByPriceAndNoncePop(t *testing.T) {
+	var senderA, senderB, to common.Address
+	senderA[0], senderB[0], to[0] = 1, 2, 3
+
+	newTx := func(nonce, gasPrice uint64) *types.Transaction {
+		return types.NewTransaction(nonce, gasPrice, 21000, big.NewInt(0), nil, senderA, to)
+	}
+
+	txs := map[common.Address]types.Transactions{
+		senderA: {newTx(0, 100), newTx(1, 100)},
+		senderB: {newTx(0, 1)},
+	}
+
+	b := NewByPriceAndNonce(txs)
+	head := b.Peek()
+	if head == nil || head.Nonce() != 0 {
+		t.Fatalf("expected senderA's nonce-0 tx first, got %v", head)
+	}
+	b.Pop()
+
+	// senderA's whole account is gone, including its unconsumed nonce-1 tx.
+	tx := b.Peek()
+	if tx == nil {
+		t.Fatalf("expected senderB's tx to remain after Pop")
+	}
+	if tx.GasPrice() != 1 {
+		t.Fatalf("expected senderB's remaining tx, got gas price %d", tx.GasPrice())
+	}
+	b.Shift()
+	if tx := b.Peek(); tx != nil {
+		t.Fatalf("expected pool exhausted after popping senderA and shifting past senderB, got %v", tx)
+	}
+}
@@ -0,0 +1,112 @@
+package txpool
+
+import (
+	"github.com/tinychain/tinychain/common"
+	"github.com/tinychain/tinychain/core/types"
+)
+
+// Pool holds every pending transaction, grouped by sender, and bounds
+// the pool's total serialized size: once Add pushes the total past Cap,
+// it evicts the lowest-gas-price transaction and every higher-nonce
+// transaction from the same sender (those can no longer execute without
+// it), repeatedly, until the pool is back under the limit.
+type Pool struct {
+	Cap uint64
+
+	size     uint64
+	accounts map[common.Address]types.NonceSortedList
+}
+
+// NewPool returns an empty Pool bounded to cap total bytes of
+// Transaction.Size().
+func NewPool(cap uint64) *Pool {
+	return &Pool{
+		Cap:      cap,
+		accounts: make(map[common.Address]types.NonceSortedList),
+	}
+}
+
+// Add admits tx into from's pending set and evicts the pool-wide
+// cheapest transaction(s) until the total size is back at or under Cap.
+// from must be the sender recovered via Signer.Sender, not tx.From().
+//
+// Add rejects tx outright, before touching p.accounts, if it fails the
+// pool's per-type admission rules: a BlobTx must carry its sidecar
+// (ValidateSidecar), and an AccessListTx's access list must be within
+// DefaultAccessListCap with no duplicate addresses (AccessList.Validate).
+func (p *Pool) Add(from common.Address, tx *types.Transaction) error {
+	if err := tx.ValidateSidecar(); err != nil {
+		return err
+	}
+	if al, ok := tx.AccessList(); ok {
+		if err := al.Validate(types.DefaultAccessListCap); err != nil {
+			return err
+		}
+	}
+	p.accounts[from] = append(p.accounts[from], tx)
+	p.size += uint64(tx.Size())
+	p.evict()
+	return nil
+}
+
+// Size returns the pool's total transaction size in bytes.
+func (p *Pool) Size() uint64 {
+	return p.size
+}
+
+// Pending snapshots the pool's current contents, grouped by sender, for
+// handing to NewByPriceAndNonce.
+func (p *Pool) Pending() map[common.Address]types.Transactions {
+	pending := make(map[common.Address]types.Transactions, len(p.accounts))
+	for from, txs := range p.accounts {
+		pending[from] = append(types.Transactions(nil), types.Transactions(txs)...)
+	}
+	return pending
+}
+
+// evict drops the pool's single cheapest transaction, and with it every
+// other pending transaction from the same sender with a higher nonce
+// (they can never execute once the cheaper, lower-nonce tx is gone),
+// repeatedly, until the pool's total size is at or under Cap.
+func (p *Pool) evict() {
+	for p.size > p.Cap {
+		from, idx, ok := p.cheapest()
+		if !ok {
+			return
+		}
+		p.evictFrom(from, p.accounts[from][idx].Nonce())
+	}
+}
+
+// evictFrom drops every pending transaction from sender with a nonce at
+// or above nonce.
+func (p *Pool) evictFrom(from common.Address, nonce uint64) {
+	txs := p.accounts[from]
+	kept := txs[:0]
+	for _, tx := range txs {
+		if tx.Nonce() < nonce {
+			kept = append(kept, tx)
+		} else {
+			p.size -= uint64(tx.Size())
+		}
+	}
+	if len(kept) == 0 {
+		delete(p.accounts, from)
+	} else {
+		p.accounts[from] = kept
+	}
+}
+
+// cheapest scans every pending transaction for the one with the lowest
+// gas price, returning its sender and index within that sender's list.
+func (p *Pool) cheapest() (from common.Address, idx int, ok bool) {
+	var lowest uint64
+	for acc, txs := range p.accounts {
+		for i, tx := range txs {
+			if !ok || tx.GasPrice() < lowest {
+				from, idx, lowest, ok = acc, i, tx.GasPrice(), true
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,83 @@
+package txpool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tinychain/tinychain/common"
+	"github.com/tinychain/tinychain/core/types"
+)
+
+func newSignedTx(nonce, gasPrice uint64, from, to common.Address) *types.Transaction {
+	tx := types.NewTransaction(nonce, gasPrice, 21000, big.NewInt(0), nil, from, to)
+	tx.PubKey = []byte("a-realistic-sized-public-key-xxxxxxxx")
+	tx.Signature = []byte("a-realistic-sized-signature-xxxxxxxxxxxxxxxxxx")
+	return tx
+}
+
+// TestPoolSizeIncludesSignature guards the Pool-level consequence of the
+// chunk0-1 MarshalBinary bug: Size() (and so Cap accounting) must reflect
+// the tx's real on-wire size, signature included, not the smaller size a
+// signature-dropping encoding would have reported.
+func TestPoolSizeIncludesSignature(t *testing.T) {
+	var from, to common.Address
+	from[0], to[0] = 1, 2
+	tx := newSignedTx(0, 10, from, to)
+
+	p := NewPool(1 << 20)
+	if err := p.Add(from, tx); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	if p.Size() != uint64(len(data)) {
+		t.Fatalf("pool size %d does not match tx's real wire size %d", p.Size(), len(data))
+	}
+	if p.Size() <= uint64(len(tx.PubKey)+len(tx.Signature)) {
+		t.Fatalf("pool size %d looks too small to include PubKey/Signature", p.Size())
+	}
+}
+
+// TestPoolEvictsLowestPriceOnCapExceeded exercises the size-based eviction
+// this request asked for, using realistic (signature-included) tx sizes
+// rather than sizes an under-counting MarshalBinary would have reported.
+func TestPoolEvictsLowestPriceOnCapExceeded(t *testing.T) {
+	var from, to common.Address
+	from[0], to[0] = 1, 2
+
+	cheap := newSignedTx(1, 1, from, to)
+	expensive := newSignedTx(0, 100, from, to)
+	cheapSize, err := cheap.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	expensiveSize, err := expensive.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	// Cap room for exactly one of the two txs.
+	cap := uint64(len(cheapSize))
+	if len(expensiveSize) > len(cheapSize) {
+		cap = uint64(len(expensiveSize))
+	}
+
+	p := NewPool(cap)
+	if err := p.Add(from, cheap); err != nil {
+		t.Fatalf("Add cheap: %v", err)
+	}
+	if err := p.Add(from, expensive); err != nil {
+		t.Fatalf("Add expensive: %v", err)
+	}
+
+	pending := p.Pending()[from]
+	if len(pending) != 1 {
+		t.Fatalf("expected eviction down to 1 pending tx, got %d", len(pending))
+	}
+	if pending[0].GasPrice() != 100 {
+		t.Fatalf("expected the cheap tx to be evicted, kept gas price %d", pending[0].GasPrice())
+	}
+}
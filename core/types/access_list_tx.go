@@ -0,0 +1,181 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/tinychain/tinychain/common"
+	"github.com/tinychain/tinychain/core/rlp"
+
+	json "github.com/json-iterator/go"
+)
+
+const (
+	// AccessListTxType is the EIP-2930-style tx carrying a pre-warming
+	// access list alongside its legacy fields.
+	AccessListTxType TxType = 0x01
+)
+
+// Gas costs for address/storage accesses during execution, matching the
+// EIP-2930 warm/cold split. AccessGasTracker returns these instead of a
+// single flat cost; nothing in this package charges them during execution.
+const (
+	ColdAccountAccessGas = 2600
+	ColdSloadGas         = 2100
+	WarmStorageReadGas   = 100
+)
+
+// DefaultAccessListCap bounds the number of tuples the pool will admit in
+// a single AccessListTx.
+const DefaultAccessListCap = 256
+
+var (
+	ErrAccessListTooLarge   = errors.New("access list exceeds maximum size")
+	ErrDuplicateAccessTuple = errors.New("access list contains a duplicate address")
+)
+
+// AccessTuple is a single address plus the storage slots under it that a
+// transaction declares it will touch, so the state-transition layer can
+// warm them up-front instead of charging the cold-access price the first
+// time they're touched.
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storage_keys"`
+}
+
+// AccessList is the ordered set of AccessTuples carried by an
+// AccessListTx.
+type AccessList []AccessTuple
+
+// Validate rejects access lists that are too large or declare the same
+// address more than once, as enforced by the tx pool on admission.
+func (al AccessList) Validate(cap int) error {
+	if len(al) > cap {
+		return ErrAccessListTooLarge
+	}
+	seen := make(map[common.Address]bool, len(al))
+	for _, tuple := range al {
+		if seen[tuple.Address] {
+			return ErrDuplicateAccessTuple
+		}
+		seen[tuple.Address] = true
+	}
+	return nil
+}
+
+// AccessListTx is an EIP-2930-style transaction: the same fields as
+// LegacyTx plus an AccessList of addresses/storage slots to pre-warm.
+type AccessListTx struct {
+	Nonce      uint64         `json:"nonce"`
+	GasPrice   uint64         `json:"gas_price"`
+	GasLimit   uint64         `json:"gas_limit"`
+	Value      *big.Int       `json:"value"`
+	From       common.Address `json:"from"`
+	To         common.Address `json:"to"`
+	Payload    []byte         `json:"payload"`
+	AccessList AccessList     `json:"access_list"`
+}
+
+func NewAccessListTx(nonce, gasPrice, gasLimit uint64, value *big.Int, payload []byte, from, to common.Address, accessList AccessList) *AccessListTx {
+	return &AccessListTx{
+		Nonce:      nonce,
+		GasPrice:   gasPrice,
+		GasLimit:   gasLimit,
+		Value:      value,
+		Payload:    payload,
+		From:       from,
+		To:         to,
+		AccessList: accessList,
+	}
+}
+
+// AccessList returns tx's access list and true if tx is an AccessListTx,
+// or false otherwise.
+func (tx *Transaction) AccessList() (AccessList, bool) {
+	if al, ok := tx.inner.(*AccessListTx); ok {
+		return al.AccessList, true
+	}
+	return nil, false
+}
+
+func (tx *AccessListTx) txType() TxType             { return AccessListTxType }
+func (tx *AccessListTx) nonce() uint64              { return tx.Nonce }
+func (tx *AccessListTx) gasPrice() uint64           { return tx.GasPrice }
+func (tx *AccessListTx) gasLimit() uint64           { return tx.GasLimit }
+func (tx *AccessListTx) value() *big.Int            { return tx.Value }
+func (tx *AccessListTx) from() common.Address       { return tx.From }
+func (tx *AccessListTx) to() common.Address         { return tx.To }
+func (tx *AccessListTx) payload() []byte            { return tx.Payload }
+func (tx *AccessListTx) Serialize() ([]byte, error) { return json.Marshal(tx) }
+func (tx *AccessListTx) Deserialize(d []byte) error { return json.Unmarshal(d, tx) }
+func (tx *AccessListTx) Encode() ([]byte, error)    { return rlp.EncodeToBytes(tx) }
+func (tx *AccessListTx) Decode(d []byte) error      { return rlp.DecodeBytes(d, tx) }
+
+// AccessGasTracker records which addresses and storage slots have been
+// accessed during a single transaction's execution, implementing the
+// EIP-2930 warm/cold accounting: a state-transition layer would create
+// one per executed tx, warm it from the tx's AccessList (plus the sender
+// and recipient, which are always warm), and call AddressGas/StorageGas
+// on every subsequent access instead of charging a flat cold-access cost.
+//
+// This repo has no state-transition/VM package yet, so nothing actually
+// creates or calls an AccessGasTracker outside its own tests: wiring it
+// into real tx execution is follow-up work, not something this type does
+// on its own.
+type AccessGasTracker struct {
+	addresses map[common.Address]bool
+	slots     map[common.Address]map[common.Hash]bool
+}
+
+func NewAccessGasTracker() *AccessGasTracker {
+	return &AccessGasTracker{
+		addresses: make(map[common.Address]bool),
+		slots:     make(map[common.Address]map[common.Hash]bool),
+	}
+}
+
+// Warm pre-warms the tracker from a transaction's access list and its
+// sender/recipient, per EIP-2930.
+func (t *AccessGasTracker) Warm(al AccessList, sender, to common.Address) {
+	t.addresses[sender] = true
+	t.addresses[to] = true
+	for _, tuple := range al {
+		t.addresses[tuple.Address] = true
+		if len(tuple.StorageKeys) == 0 {
+			continue
+		}
+		slots, ok := t.slots[tuple.Address]
+		if !ok {
+			slots = make(map[common.Hash]bool, len(tuple.StorageKeys))
+			t.slots[tuple.Address] = slots
+		}
+		for _, key := range tuple.StorageKeys {
+			slots[key] = true
+		}
+	}
+}
+
+// AddressGas returns the gas to charge for accessing addr, marking it
+// warm for the remainder of the transaction.
+func (t *AccessGasTracker) AddressGas(addr common.Address) uint64 {
+	if t.addresses[addr] {
+		return WarmStorageReadGas
+	}
+	t.addresses[addr] = true
+	return ColdAccountAccessGas
+}
+
+// StorageGas returns the gas to charge for reading slot under addr,
+// marking it warm for the remainder of the transaction.
+func (t *AccessGasTracker) StorageGas(addr common.Address, slot common.Hash) uint64 {
+	slots, ok := t.slots[addr]
+	if !ok {
+		slots = make(map[common.Hash]bool)
+		t.slots[addr] = slots
+	}
+	if slots[slot] {
+		return WarmStorageReadGas
+	}
+	slots[slot] = true
+	return ColdSloadGas
+}
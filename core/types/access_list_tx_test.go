@@ -0,0 +1,64 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/tinychain/tinychain/common"
+)
+
+func TestAccessGasTrackerWarmCold(t *testing.T) {
+	var sender, to, warmed, cold common.Address
+	sender[0], to[0], warmed[0], cold[0] = 1, 2, 3, 4
+	var slot common.Hash
+	slot[0] = 5
+
+	al := AccessList{{Address: warmed, StorageKeys: []common.Hash{slot}}}
+	tracker := NewAccessGasTracker()
+	tracker.Warm(al, sender, to)
+
+	if got := tracker.AddressGas(sender); got != WarmStorageReadGas {
+		t.Fatalf("sender should be pre-warmed, got %d", got)
+	}
+	if got := tracker.AddressGas(to); got != WarmStorageReadGas {
+		t.Fatalf("recipient should be pre-warmed, got %d", got)
+	}
+	if got := tracker.AddressGas(warmed); got != WarmStorageReadGas {
+		t.Fatalf("access-listed address should be pre-warmed, got %d", got)
+	}
+
+	if got := tracker.AddressGas(cold); got != ColdAccountAccessGas {
+		t.Fatalf("first access to unlisted address should be cold, got %d", got)
+	}
+	if got := tracker.AddressGas(cold); got != WarmStorageReadGas {
+		t.Fatalf("second access to same address should be warm, got %d", got)
+	}
+
+	if got := tracker.StorageGas(warmed, slot); got != WarmStorageReadGas {
+		t.Fatalf("access-listed slot should be pre-warmed, got %d", got)
+	}
+	var coldSlot common.Hash
+	coldSlot[0] = 6
+	if got := tracker.StorageGas(warmed, coldSlot); got != ColdSloadGas {
+		t.Fatalf("first access to unlisted slot should be cold, got %d", got)
+	}
+	if got := tracker.StorageGas(warmed, coldSlot); got != WarmStorageReadGas {
+		t.Fatalf("second access to same slot should be warm, got %d", got)
+	}
+}
+
+func TestAccessListValidate(t *testing.T) {
+	var a, b common.Address
+	a[0], b[0] = 1, 2
+
+	al := AccessList{{Address: a}, {Address: b}}
+	if err := al.Validate(2); err != nil {
+		t.Fatalf("list within cap with no duplicates should validate, got %v", err)
+	}
+	if err := al.Validate(1); err != ErrAccessListTooLarge {
+		t.Fatalf("expected ErrAccessListTooLarge, got %v", err)
+	}
+	dup := AccessList{{Address: a}, {Address: a}}
+	if err := dup.Validate(2); err != ErrDuplicateAccessTuple {
+		t.Fatalf("expected ErrDuplicateAccessTuple, got %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/tinychain/tinychain/common"
+	"github.com/tinychain/tinychain/core/rlp"
+
+	json "github.com/json-iterator/go"
+)
+
+const (
+	// BlobTxType carries a signed list of blob commitments plus an
+	// optional, unsigned sidecar of the raw blob data.
+	BlobTxType TxType = 0x02
+)
+
+var ErrMissingSidecar = errors.New("blob tx is missing its sidecar")
+
+// BlobSidecar holds the raw blobs and commitments referenced by a BlobTx's
+// BlobHashes. It is never part of the signed payload: it travels with the
+// transaction through the pool and gossip, but is stripped before the tx
+// is included in a block.
+type BlobSidecar struct {
+	Blobs       [][]byte `json:"blobs"`
+	Commitments [][]byte `json:"commitments"`
+}
+
+// BlobTx is a transaction that references external blob data by hash.
+// The hashes are part of the signed body; the sidecar carrying the
+// actual blobs is attached separately and is never hashed or signed.
+type BlobTx struct {
+	Nonce      uint64         `json:"nonce"`
+	GasPrice   uint64         `json:"gas_price"`
+	GasLimit   uint64         `json:"gas_limit"`
+	Value      *big.Int       `json:"value"`
+	From       common.Address `json:"from"`
+	To         common.Address `json:"to"`
+	Payload    []byte         `json:"payload"`
+	BlobHashes []common.Hash  `json:"blob_hashes"`
+
+	sidecar *BlobSidecar // unexported: outside the signed/hashed payload
+}
+
+func NewBlobTx(nonce, gasPrice, gasLimit uint64, value *big.Int, payload []byte, from, to common.Address, blobHashes []common.Hash) *BlobTx {
+	return &BlobTx{
+		Nonce:      nonce,
+		GasPrice:   gasPrice,
+		GasLimit:   gasLimit,
+		Value:      value,
+		Payload:    payload,
+		From:       from,
+		To:         to,
+		BlobHashes: blobHashes,
+	}
+}
+
+func (tx *BlobTx) txType() TxType       { return BlobTxType }
+func (tx *BlobTx) nonce() uint64        { return tx.Nonce }
+func (tx *BlobTx) gasPrice() uint64     { return tx.GasPrice }
+func (tx *BlobTx) gasLimit() uint64     { return tx.GasLimit }
+func (tx *BlobTx) value() *big.Int      { return tx.Value }
+func (tx *BlobTx) from() common.Address { return tx.From }
+func (tx *BlobTx) to() common.Address   { return tx.To }
+func (tx *BlobTx) payload() []byte      { return tx.Payload }
+
+// Serialize only ever encodes the signed fields above: the sidecar field
+// is unexported, so json.Marshal never touches it. That's what strips the
+// sidecar when a BlobTx is serialized for block inclusion or for the bmt
+// tx-root (see Transactions.Hash/Commit). Hash()/Verify() stability comes
+// from Encode below, not from Serialize.
+func (tx *BlobTx) Serialize() ([]byte, error) { return json.Marshal(tx) }
+func (tx *BlobTx) Deserialize(d []byte) error { return json.Unmarshal(d, tx) }
+
+// Encode/Decode are the RLP counterpart to Serialize/Deserialize (see
+// TxData). Like json.Marshal, the rlp encoder skips unexported fields,
+// so the sidecar stays out of the signed payload here too.
+func (tx *BlobTx) Encode() ([]byte, error) { return rlp.EncodeToBytes(tx) }
+func (tx *BlobTx) Decode(d []byte) error   { return rlp.DecodeBytes(d, tx) }
+
+// WithSidecar returns a shallow copy of tx with its blob sidecar attached
+// (or replaced). It is a no-op copy for non-blob tx types.
+func (tx *Transaction) WithSidecar(sidecar *BlobSidecar) *Transaction {
+	cp := *tx
+	if blob, ok := cp.inner.(*BlobTx); ok {
+		blobCp := *blob
+		blobCp.sidecar = sidecar
+		cp.inner = &blobCp
+	}
+	return &cp
+}
+
+// WithoutSidecar returns a shallow copy of tx with its blob sidecar
+// detached, suitable for block-inclusion serialization.
+func (tx *Transaction) WithoutSidecar() *Transaction {
+	return tx.WithSidecar(nil)
+}
+
+// Sidecar returns the attached blob sidecar, or nil if tx isn't a BlobTx
+// or doesn't currently carry one.
+func (tx *Transaction) Sidecar() *BlobSidecar {
+	if blob, ok := tx.inner.(*BlobTx); ok {
+		return blob.sidecar
+	}
+	return nil
+}
+
+// ValidateSidecar enforces the pool's admission rule: a BlobTx must carry
+// its sidecar to be accepted.
+func (tx *Transaction) ValidateSidecar() error {
+	if tx.Type() == BlobTxType && tx.Sidecar() == nil {
+		return ErrMissingSidecar
+	}
+	return nil
+}
+
+// BlobLimbo holds the sidecars of blob txs that were removed from a block
+// during a reorg, keyed by tx hash, so that when the pool re-injects
+// those txs they can be rehydrated with their original blob data instead
+// of being rejected for a missing sidecar.
+type BlobLimbo struct {
+	mu       sync.Mutex
+	sidecars map[common.Hash]*BlobSidecar
+}
+
+func NewBlobLimbo() *BlobLimbo {
+	return &BlobLimbo{sidecars: make(map[common.Hash]*BlobSidecar)}
+}
+
+// Hold stashes tx's sidecar, keyed by its hash, for later rehydration.
+func (l *BlobLimbo) Hold(tx *Transaction) {
+	sidecar := tx.Sidecar()
+	if sidecar == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sidecars[tx.Hash()] = sidecar
+}
+
+// Rehydrate returns tx with its sidecar restored from limbo, if one was
+// held for its hash, removing it from limbo in the process.
+func (l *BlobLimbo) Rehydrate(tx *Transaction) *Transaction {
+	l.mu.Lock()
+	sidecar, ok := l.sidecars[tx.Hash()]
+	if ok {
+		delete(l.sidecars, tx.Hash())
+	}
+	l.mu.Unlock()
+	if !ok {
+		return tx
+	}
+	return tx.WithSidecar(sidecar)
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/tinychain/tinychain/common"
+)
+
+func TestBlobLimboHoldRehydrate(t *testing.T) {
+	var from, to common.Address
+	from[0], to[0] = 1, 2
+	var blobHash common.Hash
+	blobHash[0] = 3
+
+	inner := NewBlobTx(1, 2, 3, big.NewInt(100), []byte("hello"), from, to, []common.Hash{blobHash})
+	tx := NewTx(inner)
+	sidecar := &BlobSidecar{Blobs: [][]byte{[]byte("blob")}, Commitments: [][]byte{[]byte("commitment")}}
+	withSidecar := tx.WithSidecar(sidecar)
+
+	limbo := NewBlobLimbo()
+	limbo.Hold(withSidecar)
+
+	rehydrated := limbo.Rehydrate(tx.WithoutSidecar())
+	if rehydrated.Sidecar() != sidecar {
+		t.Fatalf("expected sidecar to be restored from limbo")
+	}
+
+	// A second rehydrate for the same hash misses: Hold's entry was
+	// consumed by the first Rehydrate.
+	again := limbo.Rehydrate(tx.WithoutSidecar())
+	if again.Sidecar() != nil {
+		t.Fatalf("expected miss after sidecar already rehydrated once")
+	}
+}
+
+func TestBlobLimboRehydrateMiss(t *testing.T) {
+	var from, to common.Address
+	from[0], to[0] = 4, 5
+	inner := NewBlobTx(1, 2, 3, big.NewInt(100), nil, from, to, nil)
+	tx := NewTx(inner)
+
+	limbo := NewBlobLimbo()
+	rehydrated := limbo.Rehydrate(tx)
+	if rehydrated != tx {
+		t.Fatalf("expected Rehydrate to return tx unchanged when nothing is held for it")
+	}
+}
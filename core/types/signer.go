@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/libp2p/go-libp2p-crypto"
+	"github.com/tinychain/tinychain/common"
+)
+
+var ErrInvalidSignature = errors.New("invalid transaction signature")
+
+// Signer encapsulates the rules for hashing, signing and recovering the
+// sender of a Transaction. Pulling this out of Transaction itself is what
+// lets tinychain add new signing schemes (e.g. a future chain split)
+// without touching the tx types.
+type Signer interface {
+	// Hash returns the digest that gets signed. It must commit to
+	// whatever distinguishes this signer's scheme (e.g. the chain ID) so
+	// a signature produced for one scheme can't be replayed on another.
+	Hash(tx *Transaction) common.Hash
+	// Sender recovers and verifies the address that signed tx.
+	Sender(tx *Transaction) (common.Address, error)
+	// SignatureValues turns a raw signature produced over Hash(tx), plus
+	// the signer's raw public key bytes, into the (PubKey, Signature)
+	// pair stored on the transaction.
+	SignatureValues(tx *Transaction, pubKey, sig []byte) (storedPubKey, storedSig []byte, err error)
+	// Equal reports whether s and other describe the same signing scheme
+	// (e.g. the same chain ID), so cached senders can be invalidated when
+	// a tx is re-signed under a different signer.
+	Equal(other Signer) bool
+}
+
+// EIP155Signer mixes the chain ID into the signing hash, following
+// EIP-155, so a transaction signed for one tinychain network cannot be
+// replayed on another.
+type EIP155Signer struct {
+	chainID uint64
+}
+
+// NewEIP155Signer returns a Signer bound to chainID.
+func NewEIP155Signer(chainID uint64) EIP155Signer {
+	return EIP155Signer{chainID: chainID}
+}
+
+// LatestSignerForChainID returns the latest Signer scheme tinychain
+// supports for chainID.
+func LatestSignerForChainID(chainID uint64) Signer {
+	return NewEIP155Signer(chainID)
+}
+
+func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
+	payload, _ := tx.signingPayload()
+	var chainIDBuf [8]byte
+	binary.BigEndian.PutUint64(chainIDBuf[:], s.chainID)
+	data := make([]byte, 0, len(chainIDBuf)+len(payload))
+	data = append(data, chainIDBuf[:]...)
+	data = append(data, payload...)
+	return common.Sha256(data)
+}
+
+func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Signature == nil {
+		return common.Address{}, ErrSignNotFound
+	}
+	if tx.PubKey == nil {
+		return common.Address{}, ErrPubkeyNotFound
+	}
+	pubKey, err := crypto.UnmarshalPublicKey(tx.PubKey)
+	if err != nil {
+		return common.Address{}, err
+	}
+	ok, err := pubKey.Verify(s.Hash(tx)[:], tx.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !ok {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return common.GenAddrByPubkey(pubKey)
+}
+
+func (s EIP155Signer) SignatureValues(tx *Transaction, pubKey, sig []byte) ([]byte, []byte, error) {
+	return pubKey, sig, nil
+}
+
+func (s EIP155Signer) Equal(other Signer) bool {
+	o, ok := other.(EIP155Signer)
+	return ok && o.chainID == s.chainID
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"math/big"
 	"sync/atomic"
+
 	"github.com/tinychain/tinychain/common"
 	"github.com/tinychain/tinychain/core/bmt"
+	"github.com/tinychain/tinychain/core/rlp"
 	"github.com/tinychain/tinychain/db"
 
 	json "github.com/json-iterator/go"
@@ -16,23 +18,75 @@ const (
 	MaxTxSize = 32 * 1024 // Maximum transaction size
 )
 
+// TxType identifies the kind of payload carried inside a Transaction
+// envelope, following the EIP-2718 typed-transaction model. New types can
+// be introduced over time without disturbing the encoding or hash of the
+// types that came before them.
+type TxType byte
+
+const (
+	// LegacyTxType is the original, pre-envelope transaction format. It is
+	// the only type whose binary encoding and signing payload have no
+	// type-byte prefix.
+	LegacyTxType TxType = 0x00
+)
+
 var (
-	ErrSignNotFound    = errors.New("signature not found")
-	ErrPubkeyNotFound  = errors.New("public key not found")
-	ErrAddressNotMatch = errors.New("address not match")
+	ErrSignNotFound       = errors.New("signature not found")
+	ErrPubkeyNotFound     = errors.New("public key not found")
+	ErrAddressNotMatch    = errors.New("address not match")
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
 )
 
+// TxData is the interface implemented by the inner content of a
+// Transaction envelope. Every concrete tx format (LegacyTx, AccessListTx,
+// ...) implements it so Transaction can dispatch field access and
+// encoding without knowing the concrete type ahead of time.
+type TxData interface {
+	txType() TxType
+
+	nonce() uint64
+	gasPrice() uint64
+	gasLimit() uint64
+	value() *big.Int
+	from() common.Address
+	to() common.Address
+	payload() []byte
+
+	Serialize() ([]byte, error)
+	Deserialize([]byte) error
+
+	// Encode/Decode implement the canonical RLP-style encoding used for
+	// tx hashing and signing, in place of Serialize/Deserialize's JSON.
+	Encode() ([]byte, error)
+	Decode([]byte) error
+}
+
+// Transaction is the typed envelope around a TxData payload. The envelope
+// itself carries the fields that are common to every tx type: the
+// signature and the caches derived from it.
 type Transaction struct {
-	txData
+	inner TxData
 
 	txHash atomic.Value // hash cache
 	size   atomic.Value // size cache
+	sender atomic.Value // cachedSender, populated by Sender/Verify
 
 	PubKey    []byte `json:"pub_key"`   // Public key
 	Signature []byte `json:"signature"` // Signature of tx
 }
 
-type txData struct {
+func NewTransaction(nonce, gasPrice, gasLimit uint64, value *big.Int, payload []byte, from, to common.Address) *Transaction {
+	return NewTx(NewLegacyTx(nonce, gasPrice, gasLimit, value, payload, from, to))
+}
+
+// NewTx wraps the given TxData payload in a Transaction envelope.
+func NewTx(inner TxData) *Transaction {
+	return &Transaction{inner: inner}
+}
+
+// LegacyTx is the original transaction data format, represented as type 0.
+type LegacyTx struct {
 	Nonce    uint64         `json:"nonce"`     // Account nonce, which is used to avoid double spending
 	GasPrice uint64         `json:"gas_price"` // Gas price
 	GasLimit uint64         `json:"gas_limit"` // Gas limit of a tx
@@ -42,12 +96,8 @@ type txData struct {
 	Payload  []byte         `json:"payload"`
 }
 
-func NewTransaction(nonce, gasPrice, gasLimit uint64, value *big.Int, payload []byte, from, to common.Address) *Transaction {
-	return &Transaction{txData: NewTxData(nonce, gasPrice, gasLimit, value, payload, from, to)}
-}
-
-func NewTxData(nonce, gasPrice, gasLimit uint64, value *big.Int, payload []byte, from, to common.Address) txData {
-	return txData{
+func NewLegacyTx(nonce, gasPrice, gasLimit uint64, value *big.Int, payload []byte, from, to common.Address) *LegacyTx {
+	return &LegacyTx{
 		Nonce:    nonce,
 		GasPrice: gasPrice,
 		GasLimit: gasLimit,
@@ -58,80 +108,236 @@ func NewTxData(nonce, gasPrice, gasLimit uint64, value *big.Int, payload []byte,
 	}
 }
 
-func (txd txData) Serialize() ([]byte, error) { return json.Marshal(txd) }
-func (txd txData) Deserialize(d []byte) error { return json.Unmarshal(d, txd) }
+func (tx *LegacyTx) txType() TxType             { return LegacyTxType }
+func (tx *LegacyTx) nonce() uint64              { return tx.Nonce }
+func (tx *LegacyTx) gasPrice() uint64           { return tx.GasPrice }
+func (tx *LegacyTx) gasLimit() uint64           { return tx.GasLimit }
+func (tx *LegacyTx) value() *big.Int            { return tx.Value }
+func (tx *LegacyTx) from() common.Address       { return tx.From }
+func (tx *LegacyTx) to() common.Address         { return tx.To }
+func (tx *LegacyTx) payload() []byte            { return tx.Payload }
+func (tx *LegacyTx) Serialize() ([]byte, error) { return json.Marshal(tx) }
+func (tx *LegacyTx) Deserialize(d []byte) error { return json.Unmarshal(d, tx) }
+func (tx *LegacyTx) Encode() ([]byte, error)    { return rlp.EncodeToBytes(tx) }
+func (tx *LegacyTx) Decode(d []byte) error      { return rlp.DecodeBytes(d, tx) }
+
+// Type returns the envelope's TxType.
+func (tx *Transaction) Type() TxType { return tx.inner.txType() }
+
+func (tx *Transaction) Nonce() uint64        { return tx.inner.nonce() }
+func (tx *Transaction) GasPrice() uint64     { return tx.inner.gasPrice() }
+func (tx *Transaction) GasLimit() uint64     { return tx.inner.gasLimit() }
+func (tx *Transaction) Value() *big.Int      { return tx.inner.value() }
+func (tx *Transaction) From() common.Address { return tx.inner.from() }
+func (tx *Transaction) To() common.Address   { return tx.inner.to() }
+func (tx *Transaction) Payload() []byte      { return tx.inner.payload() }
+
+// Serialize encodes the transaction as human-facing JSON. Legacy
+// transactions are flattened so the wire format matches the pre-envelope
+// shape; other types fall back to the typed binary encoding. Both cases
+// are exactly what MarshalBinary already produces.
+func (tx *Transaction) Serialize() ([]byte, error) {
+	return tx.MarshalBinary()
+}
 
-func (tx *Transaction) Serialize() ([]byte, error) { return json.Marshal(tx) }
-func (tx *Transaction) Deserialize(d []byte) error { return json.Unmarshal(d, tx) }
+func (tx *Transaction) Deserialize(d []byte) error {
+	return tx.UnmarshalBinary(d)
+}
+
+// MarshalJSON/UnmarshalJSON let Transaction slices (Transactions) marshal
+// through the standard json package using the same encoding as Serialize.
+func (tx *Transaction) MarshalJSON() ([]byte, error) { return tx.Serialize() }
+func (tx *Transaction) UnmarshalJSON(d []byte) error { return tx.Deserialize(d) }
+
+// MarshalBinary returns the canonical wire encoding of the transaction:
+// `type_byte || payload` for every type except LegacyTxType, which falls
+// back to bare JSON so its on-disk format is unchanged. Hash() does not
+// use this encoding; it hashes signingPayload's RLP bytes.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if lt, ok := tx.inner.(*LegacyTx); ok {
+		return json.Marshal(struct {
+			*LegacyTx
+			PubKey    []byte `json:"pub_key"`
+			Signature []byte `json:"signature"`
+		}{lt, tx.PubKey, tx.Signature})
+	}
+	body, err := json.Marshal(struct {
+		Inner     TxData `json:"inner"`
+		PubKey    []byte `json:"pub_key"`
+		Signature []byte `json:"signature"`
+	}{tx.inner, tx.PubKey, tx.Signature})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(tx.Type())}, body...), nil
+}
+
+// UnmarshalBinary parses the canonical wire encoding produced by
+// MarshalBinary.
+func (tx *Transaction) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty transaction data")
+	}
+	if isLegacyEncoding(data) {
+		lt := &LegacyTx{}
+		wrapper := struct {
+			*LegacyTx
+			PubKey    []byte `json:"pub_key"`
+			Signature []byte `json:"signature"`
+		}{LegacyTx: lt}
+		if err := json.Unmarshal(data, &wrapper); err != nil {
+			return err
+		}
+		tx.inner = lt
+		tx.PubKey = wrapper.PubKey
+		tx.Signature = wrapper.Signature
+		return nil
+	}
+	inner, err := newTxData(TxType(data[0]))
+	if err != nil {
+		return err
+	}
+	wrapper := struct {
+		Inner     json.RawMessage `json:"inner"`
+		PubKey    []byte          `json:"pub_key"`
+		Signature []byte          `json:"signature"`
+	}{}
+	if err := json.Unmarshal(data[1:], &wrapper); err != nil {
+		return err
+	}
+	if err := inner.Deserialize(wrapper.Inner); err != nil {
+		return err
+	}
+	tx.inner = inner
+	tx.PubKey = wrapper.PubKey
+	tx.Signature = wrapper.Signature
+	return nil
+}
+
+// isLegacyEncoding reports whether data is bare JSON (legacy encoding)
+// rather than a `type_byte || payload` typed envelope. Every registered
+// TxType is well below '{' (0x7b), so the two encodings never collide.
+func isLegacyEncoding(data []byte) bool {
+	return len(data) > 0 && data[0] == '{'
+}
+
+func newTxData(t TxType) (TxData, error) {
+	switch t {
+	case LegacyTxType:
+		return &LegacyTx{}, nil
+	case AccessListTxType:
+		return &AccessListTx{}, nil
+	case BlobTxType:
+		return &BlobTx{}, nil
+	default:
+		return nil, ErrTxTypeNotSupported
+	}
+}
 
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.txHash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	txdata := NewTxData(tx.Nonce, tx.GasPrice, tx.GasLimit, tx.Value, tx.Payload, tx.From, tx.To)
-	data, _ := txdata.Serialize()
+	data, _ := tx.signingPayload()
 	h := common.Sha256(data)
 	tx.txHash.Store(h)
 	return h
 }
 
-// Sign the transaction with private key
-func (tx *Transaction) Sign(privKey crypto.PrivKey) ([]byte, error) {
+// signingPayload returns the bytes that are hashed and signed: the
+// inner TxData's Encode() output. Legacy transactions hash the bare
+// inner payload with no type-byte prefix; every other type is prefixed
+// with its type byte so distinct tx formats never share a hash.
+func (tx *Transaction) signingPayload() ([]byte, error) {
+	payload, err := tx.inner.Encode()
+	if err != nil {
+		return nil, err
+	}
+	if tx.Type() == LegacyTxType {
+		return payload, nil
+	}
+	return append([]byte{byte(tx.Type())}, payload...), nil
+}
+
+// Sign the transaction with privKey under the given signer. The signer
+// decides what actually gets hashed and signed (e.g. EIP155Signer mixes
+// in the chain ID), so the same tx signed under two different signers
+// produces two different, non-replayable signatures. If tx already
+// carries a Signature, Sign returns it unchanged whatever signer is
+// passed.
+func (tx *Transaction) Sign(signer Signer, privKey crypto.PrivKey) ([]byte, error) {
 	if sign := tx.Signature; sign != nil {
 		return sign, nil
 	}
-	hash := tx.Hash()
-	s, err := privKey.Sign(hash[:])
+	hash := signer.Hash(tx)
+	sig, err := privKey.Sign(hash[:])
 	if err != nil {
 		return nil, err
 	}
-	tx.Signature = s
-	tx.PubKey, err = privKey.GetPublic().Bytes()
+	rawPub, err := privKey.GetPublic().Bytes()
 	if err != nil {
 		return nil, err
 	}
-	return s, nil
-}
-
-// Verify transaction signature by specific public key
-func (tx *Transaction) Verify() (bool, error) {
-	if tx.Signature == nil {
-		return false, ErrSignNotFound
-	}
-	if tx.PubKey == nil {
-		return false, ErrPubkeyNotFound
-	}
-	pubKey, err := crypto.UnmarshalPublicKey(tx.PubKey)
+	pubKey, signature, err := signer.SignatureValues(tx, rawPub, sig)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	// Verify address
-	address, err := common.GenAddrByPubkey(pubKey)
+	tx.PubKey = pubKey
+	tx.Signature = signature
+	return signature, nil
+}
+
+// Verify recovers tx's sender under signer and checks it against the
+// self-declared From field. Unlike the pre-signer implementation, this
+// does not trust the embedded PubKey on its own: signer.Sender is the
+// single place that turns (PubKey, Signature) into a verified address.
+func (tx *Transaction) Verify(signer Signer) (bool, error) {
+	sender, err := tx.Sender(signer)
 	if err != nil {
 		return false, err
 	}
-	if address != tx.From {
+	if sender != tx.From() {
 		return false, ErrAddressNotMatch
 	}
+	return true, nil
+}
 
-	// Verify tx hash
-	hash := tx.Hash()
-	equal, err := pubKey.Verify(hash[:], tx.Signature)
+// cachedSender is what Transaction.sender holds: the resolved address
+// plus the signer that produced it, so a later call under a different
+// signer recomputes instead of reusing a different scheme's result.
+type cachedSender struct {
+	signer Signer
+	addr   common.Address
+}
+
+// Sender returns the address that signed tx under signer, recovering it
+// on first use (or whenever signer differs from whichever signer is
+// cached) and caching the result. Pool and consensus code should use
+// this rather than From(), which is only tx's self-declared sender.
+func (tx *Transaction) Sender(signer Signer) (common.Address, error) {
+	if cached := tx.sender.Load(); cached != nil {
+		cs := cached.(cachedSender)
+		if cs.signer.Equal(signer) {
+			return cs.addr, nil
+		}
+	}
+	addr, err := signer.Sender(tx)
 	if err != nil {
-		return false, err
+		return common.Address{}, err
 	}
-	return equal, nil
+	tx.sender.Store(cachedSender{signer: signer, addr: addr})
+	return addr, nil
 }
 
 func (tx *Transaction) Cost() *big.Int {
-	return new(big.Int).Add(tx.Value, new(big.Int).SetUint64(tx.GasLimit))
+	return new(big.Int).Add(tx.Value(), new(big.Int).SetUint64(tx.GasLimit()))
 }
 
 func (tx *Transaction) Size() uint32 {
 	if size := tx.size.Load(); size != nil {
 		return size.(uint32)
 	}
-	data, _ := tx.Serialize()
+	data, _ := tx.MarshalBinary()
 	size := uint32(len(data))
 	tx.size.Store(size)
 	return size
@@ -139,10 +345,14 @@ func (tx *Transaction) Size() uint32 {
 
 type Transactions []*Transaction
 
+// Hash computes the bmt root over the transactions' binary encodings.
+// Binary encoding already strips any BlobTx sidecar (it's an unexported
+// field that MarshalBinary never sees), so the root is always taken over
+// the stripped tx bodies regardless of whether a sidecar is attached.
 func (txs Transactions) Hash() common.Hash {
 	txSet := bmt.WriteSet{}
 	for _, tx := range txs {
-		data, err := tx.Serialize()
+		data, err := tx.MarshalBinary()
 		if err != nil {
 			return common.Hash{}
 		}
@@ -155,7 +365,7 @@ func (txs Transactions) Hash() common.Hash {
 func (txs Transactions) Commit(db *db.LDBDatabase) error {
 	txSet := bmt.WriteSet{}
 	for _, tx := range txs {
-		data, err := tx.Serialize()
+		data, err := tx.MarshalBinary()
 		if err != nil {
 			return err
 		}
@@ -164,6 +374,22 @@ func (txs Transactions) Commit(db *db.LDBDatabase) error {
 	return bmt.Commit(txSet, db)
 }
 
+// ProveTx returns a Merkle inclusion proof for the transaction with the
+// given hash, verifiable against txs.Hash() via bmt.VerifyProof. This is
+// what lets a light client confirm a transaction is in a block from just
+// the tx-root and this proof, without downloading the rest of txs.
+func (txs Transactions) ProveTx(hash common.Hash) (*bmt.Proof, error) {
+	txSet := bmt.WriteSet{}
+	for _, tx := range txs {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		txSet[tx.Hash().String()] = data
+	}
+	return bmt.Prove(hash.String(), txSet)
+}
+
 func (txs Transactions) Serialize() ([]byte, error) {
 	return json.Marshal(txs)
 }
@@ -188,6 +414,16 @@ func (tm *TxMeta) Deserialize(d []byte) error {
 	return json.Unmarshal(d, tm)
 }
 
+// Encode/Decode mirror TxData's RLP encoding for TxMeta.
+func (tm *TxMeta) Encode() ([]byte, error) { return rlp.EncodeToBytes(tm) }
+func (tm *TxMeta) Decode(d []byte) error   { return rlp.DecodeBytes(d, tm) }
+
+// NonceSortedList sorts one account's transactions by ascending nonce,
+// the valid execution order within a single sender. core/txpool heapifies
+// it per-sender and orders those per-sender heads across senders by gas
+// price; a single list sorted by (nonce asc, price desc) across every
+// sender, which tinychain used to use, interleaves unrelated accounts'
+// nonces and isn't a valid execution order for more than one sender.
 type NonceSortedList Transactions
 
 func (txs NonceSortedList) Len() int {
@@ -195,30 +431,9 @@ func (txs NonceSortedList) Len() int {
 }
 
 func (txs NonceSortedList) Less(i, j int) bool {
-	return txs[i].Nonce < txs[j].Nonce
+	return txs[i].Nonce() < txs[j].Nonce()
 }
 
 func (txs NonceSortedList) Swap(i, j int) {
 	txs[i], txs[j] = txs[j], txs[i]
 }
-
-// Nonce-asec-sorted and price-desec-sorted list
-type SortedList Transactions
-
-func (txs SortedList) Len() int {
-	return len(txs)
-}
-
-func (txs SortedList) Less(i, j int) bool {
-	if txs[i].Nonce < txs[j].Nonce {
-		return true
-	} else if txs[i].Nonce == txs[j].Nonce {
-		return txs[i].GasPrice > txs[j].GasPrice
-	} else {
-		return false
-	}
-}
-
-func (txs SortedList) Swap(i, j int) {
-	txs[i], txs[j] = txs[j], txs[i]
-}
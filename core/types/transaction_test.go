@@ -0,0 +1,95 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/tinychain/tinychain/common"
+)
+
+// checkRoundTrip encodes inner, decodes it into a fresh value of the same
+// concrete type, and asserts that re-encoding reproduces the exact same
+// bytes and that the two tx's hashes agree. This is the property
+// json-iterator couldn't guarantee across versions or map iteration, and
+// that interop/light clients depend on.
+func checkRoundTrip(t *testing.T, inner, decoded TxData) {
+	t.Helper()
+
+	encoded, err := inner.Encode()
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if err := decoded.Decode(encoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	reEncoded, err := decoded.Encode()
+	if err != nil {
+		t.Fatalf("re-encode: %v", err)
+	}
+	if !bytes.Equal(encoded, reEncoded) {
+		t.Fatalf("encoding not stable across round-trip: %x != %x", encoded, reEncoded)
+	}
+
+	tx := NewTx(inner)
+	roundTripped := NewTx(decoded)
+	if tx.Hash() != roundTripped.Hash() {
+		t.Fatalf("hash changed across round-trip: %x != %x", tx.Hash(), roundTripped.Hash())
+	}
+	if tx.Hash() != tx.Hash() {
+		t.Fatalf("Hash() is not idempotent")
+	}
+}
+
+// FuzzLegacyTxHashStability fuzzes LegacyTx's scalar/byte-slice fields.
+func FuzzLegacyTxHashStability(f *testing.F) {
+	f.Add(uint64(1), uint64(2), uint64(3), uint64(100), []byte("hello"))
+	f.Add(uint64(0), uint64(0), uint64(0), uint64(0), []byte(nil))
+
+	f.Fuzz(func(t *testing.T, nonce, gasPrice, gasLimit, value uint64, payload []byte) {
+		inner := NewLegacyTx(nonce, gasPrice, gasLimit, new(big.Int).SetUint64(value), payload, common.Address{}, common.Address{})
+		checkRoundTrip(t, inner, &LegacyTx{})
+	})
+}
+
+// TestLegacyTxMarshalBinaryPreservesSignature guards against MarshalBinary's
+// legacy branch regressing to tx.inner.Serialize(), which drops PubKey and
+// Signature since they live on the outer Transaction, not LegacyTx.
+func TestLegacyTxMarshalBinaryPreservesSignature(t *testing.T) {
+	inner := NewLegacyTx(1, 2, 3, big.NewInt(100), []byte("hello"), common.Address{}, common.Address{})
+	tx := NewTx(inner)
+	tx.PubKey = []byte("pubkey")
+	tx.Signature = []byte("signature")
+
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	roundTripped := &Transaction{}
+	if err := roundTripped.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if !bytes.Equal(roundTripped.PubKey, tx.PubKey) {
+		t.Fatalf("PubKey not preserved: got %x, want %x", roundTripped.PubKey, tx.PubKey)
+	}
+	if !bytes.Equal(roundTripped.Signature, tx.Signature) {
+		t.Fatalf("Signature not preserved: got %x, want %x", roundTripped.Signature, tx.Signature)
+	}
+}
+
+// FuzzAccessListTxHashStability fuzzes AccessListTx, which exercises the
+// slice-of-struct (AccessList) and nested []common.Hash decode paths that
+// LegacyTx never touches.
+func FuzzAccessListTxHashStability(f *testing.F) {
+	f.Add(uint64(1), uint64(2), uint64(3), uint64(100), []byte("hello"), []byte{0x01})
+	f.Add(uint64(0), uint64(0), uint64(0), uint64(0), []byte(nil), []byte(nil))
+
+	f.Fuzz(func(t *testing.T, nonce, gasPrice, gasLimit, value uint64, payload, slotKey []byte) {
+		var slot common.Hash
+		copy(slot[:], slotKey)
+		al := AccessList{{Address: common.Address{}, StorageKeys: []common.Hash{slot}}}
+		inner := NewAccessListTx(nonce, gasPrice, gasLimit, new(big.Int).SetUint64(value), payload, common.Address{}, common.Address{}, al)
+		checkRoundTrip(t, inner, &AccessListTx{})
+	})
+}